@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameInfo captures what `git blame` knows about the commit that
+// introduced a given line.
+type BlameInfo struct {
+	Commit      string
+	Author      string
+	AuthorEmail string
+	AuthoredAt  time.Time
+}
+
+// BlameLine runs `git blame --porcelain` on a single line of filename and
+// returns the commit that introduced it.
+func BlameLine(filename string, line int) (BlameInfo, error) {
+	lineRange := strconv.Itoa(line) + "," + strconv.Itoa(line)
+	cmd := LogCommand(exec.Command("git", "blame", "--porcelain", "-L", lineRange, "--", filename))
+
+	out, err := cmd.Output()
+	if err != nil {
+		return BlameInfo{}, err
+	}
+
+	var info BlameInfo
+	var authorTime int64
+
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	first := true
+
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+
+		if first {
+			info.Commit = fields[0]
+			first = false
+			continue
+		}
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "author":
+			info.Author = fields[1]
+		case "author-mail":
+			info.AuthorEmail = strings.Trim(fields[1], "<>")
+		case "author-time":
+			authorTime, err = strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return BlameInfo{}, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return BlameInfo{}, err
+	}
+
+	info.AuthoredAt = time.Unix(authorTime, 0)
+
+	return info, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"text/template"
+)
+
+// defaultBodyTemplate renders the TODO's suffix followed by a blame footer,
+// so a reported issue is immediately actionable without opening the repo.
+const defaultBodyTemplate = `{{.Suffix}}
+
+Blame: {{.Commit}} by {{.Author}} on {{.AuthoredAt.Format "2006-01-02"}}
+`
+
+// KeywordConfig describes how a single recognized marker (TODO, FIXME,
+// XXX, HACK, ...) is parsed and reported.
+type KeywordConfig struct {
+	Labels    []string `yaml:"labels"`
+	Assignees []string `yaml:"assignees"`
+	Title     string   `yaml:"title"`
+	Body      string   `yaml:"body"`
+
+	titleTemplate *template.Template
+	bodyTemplate  *template.Template
+}
+
+// RenderTitle renders this keyword's title template over todo, falling
+// back to the bare suffix if no template was configured.
+func (kc KeywordConfig) RenderTitle(todo Todo) string {
+	if kc.titleTemplate == nil {
+		return todo.Suffix
+	}
+
+	var buf bytes.Buffer
+	if err := kc.titleTemplate.Execute(&buf, todo); err != nil {
+		return todo.Suffix
+	}
+
+	return buf.String()
+}
+
+// RenderBody renders this keyword's body template over todo, falling back
+// to the default blame-footer template if none was configured.
+func (kc KeywordConfig) RenderBody(todo Todo) (string, error) {
+	tmpl := kc.bodyTemplate
+	if tmpl == nil {
+		tmpl = template.Must(template.New("body").Parse(defaultBodyTemplate))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, todo); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// SeverityRule extracts a priority label from a TODO's annotation or
+// suffix, e.g. `TODO(!!!): fix this` matching `!!!` and contributing the
+// label "priority:high".
+type SeverityRule struct {
+	Match string `yaml:"match"`
+	Label string `yaml:"label"`
+
+	regexp *regexp.Regexp
+}
+
+// Route scopes a repo override to a glob of files, so different subtrees
+// of one working copy can report to different issue trackers. Remote
+// names which git remote to auto-derive the provider/host from (default
+// "origin"); Repo, if given, overrides the owner/repo slug on top of
+// whatever that remote resolves to.
+type Route struct {
+	Path   string `yaml:"path"`
+	Remote string `yaml:"remote"`
+	Repo   string `yaml:"repo"`
+}
+
+// ProjectConfig holds the per-project settings that affect how snitch
+// parses and reports TODOs. It is assembled from one or more .snitch.yaml
+// files (see LoadProjectConfig).
+type ProjectConfig struct {
+	Keywords   map[string]KeywordConfig
+	Severities []SeverityRule
+	Routes     []Route
+
+	// Assignees maps a TODO author's git blame email to their tracker
+	// handle, so reported issues can @-mention the person who introduced
+	// the TODO.
+	Assignees map[string]string
+}
+
+// DefaultProjectConfig is used when no .snitch.yaml is found. It only
+// recognizes the bare "TODO" keyword, matching snitch's original behavior.
+func DefaultProjectConfig() ProjectConfig {
+	return ProjectConfig{
+		Keywords: map[string]KeywordConfig{
+			"TODO": {},
+		},
+	}
+}
+
+// KeywordNames returns the markers this config recognizes, e.g.
+// ["TODO", "FIXME"].
+func (c ProjectConfig) KeywordNames() []string {
+	names := make([]string, 0, len(c.Keywords))
+	for name := range c.Keywords {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Keyword looks up the configuration for a recognized marker, returning
+// the zero KeywordConfig (plain title/body, no labels/assignees) if it was
+// never explicitly configured.
+func (c ProjectConfig) Keyword(name string) KeywordConfig {
+	return c.Keywords[name]
+}
+
+// SeverityLabels returns the labels of every SeverityRule matching the
+// TODO's annotation (the text inside an unreported `KEYWORD(...)`) or its
+// suffix.
+func (c ProjectConfig) SeverityLabels(annotation, suffix string) []string {
+	var labels []string
+
+	for _, rule := range c.Severities {
+		if rule.regexp == nil {
+			continue
+		}
+
+		if rule.regexp.MatchString(annotation) || rule.regexp.MatchString(suffix) {
+			labels = append(labels, rule.Label)
+		}
+	}
+
+	return labels
+}
+
+// RouteFor returns the Route configured for filename, if any of the
+// Routes' path globs match it.
+func (c ProjectConfig) RouteFor(filename string) (route Route, ok bool) {
+	for _, route := range c.Routes {
+		if matchGitignorePattern(route.Path, filename) {
+			return route, true
+		}
+	}
+
+	return Route{}, false
+}
+
+// Assignee returns the tracker handle to @-mention for todo's author, and
+// whether their blame email is present in the Assignees table.
+func (c ProjectConfig) Assignee(todo Todo) (handle string, ok bool) {
+	handle, ok = c.Assignees[todo.AuthorEmail]
+	return handle, ok
+}
+
+// Mentions returns every tracker handle to @-mention on a reported issue
+// for todo: the keyword's configured default assignees first, followed by
+// the blame author's handle if their email is present in the project's
+// Assignees table.
+func (c ProjectConfig) Mentions(todo Todo) []string {
+	mentions := append([]string(nil), c.Keyword(todo.Keyword).Assignees...)
+
+	if handle, ok := c.Assignee(todo); ok {
+		mentions = append(mentions, handle)
+	}
+
+	return mentions
+}
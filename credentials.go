@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GithubCredentials holds the personal access token used to talk to the
+// GitHub REST API.
+type GithubCredentials struct {
+	PersonalToken string `yaml:"personal_token"`
+}
+
+// GitlabCredentials holds the personal access token used to talk to the
+// GitLab REST API.
+type GitlabCredentials struct {
+	PersonalToken string `yaml:"personal_token"`
+}
+
+// GiteaCredentials holds the host and personal access token used to talk to
+// a (self-hosted) Gitea/Gogs instance.
+type GiteaCredentials struct {
+	Host          string `yaml:"host"`
+	PersonalToken string `yaml:"personal_token"`
+}
+
+// Credentials aggregates the credentials of every issue tracker backend
+// snitch knows how to talk to. Providers the user never configured are left
+// zero-valued.
+type Credentials struct {
+	Github GithubCredentials `yaml:"github"`
+	Gitlab GitlabCredentials `yaml:"gitlab"`
+	Gitea  GiteaCredentials  `yaml:"gitea"`
+}
+
+// credentialsFile is the YAML shape of ~/.snitch/credentials.yaml.
+type credentialsFile struct {
+	Providers Credentials `yaml:"providers"`
+}
+
+// CredentialsFromFile reads a ~/.snitch/credentials.yaml file with a
+// providers: section, e.g.:
+//
+//	providers:
+//	  github:
+//	    personal_token: deadbeef
+//	  gitea:
+//	    host: gitea.example.com
+//	    personal_token: deadbeef
+//
+// A missing file is not an error; it just yields empty Credentials.
+func CredentialsFromFile(path string) (Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Credentials{}, nil
+		}
+		return Credentials{}, err
+	}
+
+	var file credentialsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Credentials{}, err
+	}
+
+	return file.Providers, nil
+}
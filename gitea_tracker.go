@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// GiteaTracker reports and tracks TODOs as issues on a self-hosted
+// Gitea/Gogs instance. Its API shape mirrors GitHub's closely enough that
+// the same repos/{owner}/{repo}/issues endpoints and token auth apply.
+type GiteaTracker struct {
+	Creds GiteaCredentials
+	Host  string
+	Repo  string
+}
+
+func (t GiteaTracker) queryAPI(method, url string, jsonBody map[string]interface{}) (map[string]interface{}, error) {
+	client := &http.Client{}
+
+	bodyBuffer := new(bytes.Buffer)
+	if err := json.NewEncoder(bodyBuffer).Encode(jsonBody); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url, bodyBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Authorization", "token "+t.Creds.PersonalToken)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if msg, ok := v["message"].(string); ok {
+			return nil, fmt.Errorf("gitea API %s %s: %d %s", method, url, resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("gitea API %s %s: unexpected status %d", method, url, resp.StatusCode)
+	}
+
+	return v, nil
+}
+
+func (t GiteaTracker) apiURL(path string) string {
+	return "https://" + t.Host + "/api/v1/repos/" + t.Repo + path
+}
+
+// Report files todo as a new issue on the configured Gitea instance.
+func (t GiteaTracker) Report(todo Todo, body string) (string, error) {
+	json, err := t.queryAPI(
+		"POST",
+		t.apiURL("/issues"),
+		map[string]interface{}{
+			"title":  todo.Title,
+			"body":   body,
+			"labels": todo.Labels,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	number, ok := json["number"].(float64)
+	if !ok {
+		return "", fmt.Errorf("gitea API: response has no \"number\": %v", json)
+	}
+
+	return "#" + strconv.Itoa(int(number)), nil
+}
+
+// Status retrieves the current state of the Gitea issue todo was reported to.
+func (t GiteaTracker) Status(todo Todo) (string, error) {
+	json, err := t.queryAPI(
+		"GET",
+		t.apiURL("/issues/"+(*todo.ID)[1:]),
+		nil)
+	if err != nil {
+		return "", err
+	}
+
+	state, ok := json["state"].(string)
+	if !ok {
+		return "", fmt.Errorf("gitea API: response has no \"state\": %v", json)
+	}
+
+	return state, nil
+}
+
+// Close closes the Gitea issue with the given id (e.g. "#42").
+func (t GiteaTracker) Close(id string) error {
+	_, err := t.queryAPI(
+		"PATCH",
+		t.apiURL("/issues/"+id[1:]),
+		map[string]interface{}{"state": "closed"})
+	return err
+}
+
+// IssueURL returns the URL of the Gitea issue todo was reported to.
+func (t GiteaTracker) IssueURL(todo Todo) string {
+	return "https://" + t.Host + "/" + t.Repo + "/issues/" + (*todo.ID)[1:]
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// GithubTracker reports and tracks TODOs as issues on github.com.
+type GithubTracker struct {
+	Creds GithubCredentials
+	Repo  string
+}
+
+func (t GithubTracker) queryAPI(method, url string, jsonBody map[string]interface{}) (map[string]interface{}, error) {
+	client := &http.Client{}
+
+	bodyBuffer := new(bytes.Buffer)
+	if err := json.NewEncoder(bodyBuffer).Encode(jsonBody); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url, bodyBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Authorization", "token "+t.Creds.PersonalToken)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if msg, ok := v["message"].(string); ok {
+			return nil, fmt.Errorf("github API %s %s: %d %s", method, url, resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("github API %s %s: unexpected status %d", method, url, resp.StatusCode)
+	}
+
+	return v, nil
+}
+
+// Report files todo as a new GitHub issue.
+func (t GithubTracker) Report(todo Todo, body string) (string, error) {
+	json, err := t.queryAPI(
+		"POST",
+		"https://api.github.com/repos/"+t.Repo+"/issues",
+		map[string]interface{}{
+			"title":  todo.Title,
+			"body":   body,
+			"labels": todo.Labels,
+		})
+	if err != nil {
+		return "", err
+	}
+
+	number, ok := json["number"].(float64)
+	if !ok {
+		return "", fmt.Errorf("github API: response has no \"number\": %v", json)
+	}
+
+	return "#" + strconv.Itoa(int(number)), nil
+}
+
+// Status retrieves the current state of the GitHub issue todo was reported to.
+func (t GithubTracker) Status(todo Todo) (string, error) {
+	json, err := t.queryAPI(
+		"GET",
+		// TODO(#59): possible GitHub API injection attack
+		"https://api.github.com/repos/"+t.Repo+"/issues/"+(*todo.ID)[1:],
+		nil)
+	if err != nil {
+		return "", err
+	}
+
+	state, ok := json["state"].(string)
+	if !ok {
+		return "", fmt.Errorf("github API: response has no \"state\": %v", json)
+	}
+
+	return state, nil
+}
+
+// Close closes the GitHub issue with the given id (e.g. "#42").
+func (t GithubTracker) Close(id string) error {
+	_, err := t.queryAPI(
+		"PATCH",
+		"https://api.github.com/repos/"+t.Repo+"/issues/"+id[1:],
+		map[string]interface{}{"state": "closed"})
+	return err
+}
+
+// IssueURL returns the URL of the GitHub issue todo was reported to.
+func (t GithubTracker) IssueURL(todo Todo) string {
+	return "https://github.com/" + t.Repo + "/issues/" + (*todo.ID)[1:]
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GitlabTracker reports and tracks TODOs as issues on gitlab.com.
+type GitlabTracker struct {
+	Creds GitlabCredentials
+	Repo  string
+}
+
+func (t GitlabTracker) queryAPI(method, endpoint string, jsonBody map[string]interface{}) (map[string]interface{}, error) {
+	client := &http.Client{}
+
+	bodyBuffer := new(bytes.Buffer)
+	if err := json.NewEncoder(bodyBuffer).Encode(jsonBody); err != nil {
+		return nil, err
+	}
+
+	fullURL := "https://gitlab.com/api/v4/projects/" + url.PathEscape(t.Repo) + endpoint
+
+	req, err := http.NewRequest(method, fullURL, bodyBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("PRIVATE-TOKEN", t.Creds.PersonalToken)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if msg, ok := v["message"].(string); ok {
+			return nil, fmt.Errorf("gitlab API %s %s: %d %v", method, fullURL, resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("gitlab API %s %s: unexpected status %d", method, fullURL, resp.StatusCode)
+	}
+
+	return v, nil
+}
+
+// Report files todo as a new GitLab issue.
+func (t GitlabTracker) Report(todo Todo, body string) (string, error) {
+	json, err := t.queryAPI(
+		"POST",
+		"/issues",
+		map[string]interface{}{
+			"title":       todo.Title,
+			"description": body,
+			"labels":      strings.Join(todo.Labels, ","),
+		})
+	if err != nil {
+		return "", err
+	}
+
+	iid, ok := json["iid"].(float64)
+	if !ok {
+		return "", fmt.Errorf("gitlab API: response has no \"iid\": %v", json)
+	}
+
+	return "#" + strconv.Itoa(int(iid)), nil
+}
+
+// Status retrieves the current state of the GitLab issue todo was reported to.
+func (t GitlabTracker) Status(todo Todo) (string, error) {
+	json, err := t.queryAPI(
+		"GET",
+		"/issues/"+(*todo.ID)[1:],
+		nil)
+	if err != nil {
+		return "", err
+	}
+
+	state, ok := json["state"].(string)
+	if !ok {
+		return "", fmt.Errorf("gitlab API: response has no \"state\": %v", json)
+	}
+
+	// GitLab reports "opened"/"closed" rather than GitHub's "open"/"closed".
+	if state == "opened" {
+		return "open", nil
+	}
+
+	return "closed", nil
+}
+
+// Close closes the GitLab issue with the given id (e.g. "#42").
+func (t GitlabTracker) Close(id string) error {
+	_, err := t.queryAPI(
+		"PUT",
+		"/issues/"+id[1:],
+		map[string]interface{}{"state_event": "close"})
+	return err
+}
+
+// IssueURL returns the URL of the GitLab issue todo was reported to.
+func (t GitlabTracker) IssueURL(todo Todo) string {
+	return "https://gitlab.com/" + t.Repo + "/-/issues/" + (*todo.ID)[1:]
+}
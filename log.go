@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// LogCommand prints the command about to be executed before running it, so
+// the user can see exactly what snitch is doing to their git tree.
+func LogCommand(cmd *exec.Cmd) *exec.Cmd {
+	fmt.Printf("[EXEC] %v\n", cmd.Args)
+	return cmd
+}
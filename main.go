@@ -4,23 +4,84 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"os/user"
 	"path"
+	"strings"
 )
 
+// trackerFor resolves the IssueTracker a todo should be reported to.
+func trackerFor(creds Credentials, projectConfig ProjectConfig, repoArg string, todo Todo) (IssueTracker, error) {
+	repo, err := repoArgFor(projectConfig, repoArg, todo.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResolveTracker(creds, repo)
+}
+
+// repoArgFor figures out the "[provider:]owner/repo" argument to hand
+// ResolveTracker for filename.
+//
+// An explicit repoArg (from the command line) always wins. Otherwise, a
+// Route whose path glob matches filename picks which git remote to
+// resolve the provider/host from (default "origin") and, if given,
+// overrides the owner/repo slug on top of it. Lacking a match, the
+// "origin" remote is resolved directly.
+func repoArgFor(projectConfig ProjectConfig, repoArg, filename string) (string, error) {
+	if repoArg != "" {
+		return repoArg, nil
+	}
+
+	resolver := DefaultRepoResolver()
+	var repoOverride string
+
+	if route, ok := projectConfig.RouteFor(filename); ok {
+		if route.Remote != "" {
+			resolver.RemoteName = route.Remote
+		}
+		repoOverride = route.Repo
+	}
+
+	info, err := resolver.Resolve()
+	if err != nil {
+		return "", err
+	}
+
+	if repoOverride != "" {
+		// For self-hosted providers info.Repo is "host/owner/repo"; a route
+		// override that only gives "owner/repo" replaces just the slug, not
+		// the host resolved from the remote, or splitHostRepo would later
+		// misread the owner as the host.
+		if info.Provider == "gitea" && strings.Count(repoOverride, "/") < 2 {
+			host := strings.SplitN(info.Repo, "/", 2)[0]
+			repoOverride = host + "/" + repoOverride
+		}
+		info.Repo = repoOverride
+	}
+
+	return info.RepoArg(), nil
+}
+
 func listSubcommand() error {
-	return WalkTodosOfDir(".", func(todo Todo) error {
+	return WalkTodosOfDir(".", ".", false, func(_ ProjectConfig, todo Todo) error {
 		fmt.Printf("%v\n", todo.LogString())
 		return nil
 	})
 }
 
-func reportSubcommand(creds GithubCredentials, repo string) error {
-	todosToReport := []Todo{}
+// pendingReport pairs a not-yet-reported Todo with the ProjectConfig that
+// applies to it, so reportSubcommand can render its body/assignee with the
+// right per-directory overrides once the user has confirmed it.
+type pendingReport struct {
+	todo   Todo
+	config ProjectConfig
+}
+
+func reportSubcommand(creds Credentials, repoArg string) error {
+	todosToReport := []pendingReport{}
 	reader := bufio.NewReader(os.Stdin)
 
-	err := WalkTodosOfDir(".", func(todo Todo) error {
+	err := WalkTodosOfDir(".", ".", true, func(projectConfig ProjectConfig, todo Todo) error {
 		if todo.ID == nil {
 			fmt.Printf("%v\n", todo.LogString())
 
@@ -39,7 +100,7 @@ func reportSubcommand(creds GithubCredentials, repo string) error {
 				return nil
 			}
 
-			todosToReport = append(todosToReport, todo)
+			todosToReport = append(todosToReport, pendingReport{todo: todo, config: projectConfig})
 		}
 
 		return nil
@@ -49,27 +110,35 @@ func reportSubcommand(creds GithubCredentials, repo string) error {
 		return err
 	}
 
-	for _, todo := range todosToReport {
-		reportedTodo, err := ReportTodo(todo, creds, repo)
+	for _, pending := range todosToReport {
+		todo, projectConfig := pending.todo, pending.config
 
+		tracker, err := trackerFor(creds, projectConfig, repoArg, todo)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("[REPORTED] %v\n", reportedTodo.LogString())
-
-		err = reportedTodo.UpdateInPlace()
+		body, err := projectConfig.Keyword(todo.Keyword).RenderBody(todo)
 		if err != nil {
 			return err
 		}
 
-		err = exec.Command("git", "add", reportedTodo.Filename).Run()
+		if mentions := projectConfig.Mentions(todo); len(mentions) > 0 {
+			body = "@" + strings.Join(mentions, " @") + " " + body
+		}
+
+		reportedTodo, err := todo.Report(tracker, body)
 		if err != nil {
 			return err
 		}
 
-		err = exec.Command("git", "commit", "-m", reportedTodo.CommitMessage()).Run()
-		if err != nil {
+		fmt.Printf("[REPORTED] %v\n", reportedTodo.LogString())
+
+		if err := reportedTodo.Update(); err != nil {
+			return err
+		}
+
+		if err := reportedTodo.GitCommit("Report"); err != nil {
 			return err
 		}
 	}
@@ -81,7 +150,22 @@ func usage() {
 	// TODO(#9): implement a map for options instead of println'ing them all there
 	fmt.Printf("snitch [opt]\n" +
 		"\tlist: lists all todos of a dir recursively\n" +
-		"\treport <owner/repo>: reports an issue to github\n")
+		"\treport [[provider:]owner/repo]: reports an issue to the given\n" +
+		"\t\ttracker (github, gitlab or gitea:host/owner/repo); provider\n" +
+		"\t\tdefaults to github. If omitted, the repo is derived from the\n" +
+		"\t\t\"origin\" git remote (or .snitch.yaml routes)\n" +
+		"\twatch [[provider:]owner/repo]: like report, but runs forever,\n" +
+		"\t\tincrementally filing, closing and removing TODOs\n")
+}
+
+// repoArgFromArgs returns the optional trailing "[provider:]owner/repo"
+// argument to a report/watch invocation, or "" if none was given.
+func repoArgFromArgs(args []string) string {
+	if len(args) < 3 {
+		return ""
+	}
+
+	return args[2]
 }
 
 func main() {
@@ -90,8 +174,8 @@ func main() {
 		panic(err)
 	}
 
-	creds, err := GithubCredentialsFromFile(
-		path.Join(usr.HomeDir, ".snitch/github.ini"))
+	creds, err := CredentialsFromFile(
+		path.Join(usr.HomeDir, ".snitch/credentials.yaml"))
 	if err != nil {
 		panic(err)
 	}
@@ -102,12 +186,9 @@ func main() {
 		case "list":
 			listSubcommand()
 		case "report":
-			if len(os.Args) < 3 {
-				usage()
-				panic("Not enough arguments")
-			}
-			// TODO(#24): GitHub repo is not automatically derived from the git repo
-			reportSubcommand(creds, os.Args[2])
+			reportSubcommand(creds, repoArgFromArgs(os.Args))
+		case "watch":
+			watchSubcommand(creds, repoArgFromArgs(os.Args))
 		default:
 			panic(fmt.Sprintf("`%s` unknown command", os.Args[1]))
 		}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitattributesRule is one line of a .gitattributes file: a path pattern
+// plus the attributes it assigns, e.g. "*.bin binary" or
+// "vendor/** linguist-vendored=true". dir is the directory the
+// .gitattributes file lives in, relative to the repo root.
+type gitattributesRule struct {
+	dir        string
+	pattern    string
+	attributes map[string]string
+}
+
+// PathFilter decides, for a path under the repo root, whether it should be
+// scanned for TODOs at all and which keyword prefix to look for. It is
+// built by layering every .gitattributes file from the repo root down to
+// each directory, with deeper rules overriding shallower ones -- the same
+// precedence git itself uses when resolving attributes.
+type PathFilter struct {
+	rules []gitattributesRule
+}
+
+// NewPathFilter loads every .gitattributes file found under root.
+func NewPathFilter(root string) (*PathFilter, error) {
+	filter := &PathFilter{}
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Base(p) != ".gitattributes" {
+			return nil
+		}
+
+		dir, err := filepath.Rel(root, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+
+		rules, err := parseGitattributes(p, filepath.ToSlash(dir))
+		if err != nil {
+			return err
+		}
+
+		filter.rules = append(filter.rules, rules...)
+		return nil
+	})
+
+	return filter, err
+}
+
+func parseGitattributes(filename, dir string) ([]gitattributesRule, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []gitattributesRule
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		attributes := map[string]string{}
+
+		for _, attr := range fields[1:] {
+			switch {
+			case strings.HasPrefix(attr, "-"):
+				attributes[attr[1:]] = "false"
+			case strings.Contains(attr, "="):
+				kv := strings.SplitN(attr, "=", 2)
+				attributes[kv[0]] = kv[1]
+			default:
+				attributes[attr] = "true"
+			}
+		}
+
+		rules = append(rules, gitattributesRule{dir: dir, pattern: fields[0], attributes: attributes})
+	}
+
+	return rules, scanner.Err()
+}
+
+// attributesFor resolves the effective attributes for relPath (relative to
+// the repo root), applying every matching rule in root-to-leaf order so
+// that deeper and later rules win.
+func (f *PathFilter) attributesFor(relPath string) map[string]string {
+	attributes := map[string]string{}
+
+	for _, rule := range f.rules {
+		scopedPath := relPath
+
+		if rule.dir != "." {
+			prefix := rule.dir + "/"
+			if !strings.HasPrefix(relPath, prefix) {
+				continue
+			}
+			scopedPath = strings.TrimPrefix(relPath, prefix)
+		}
+
+		if !matchGitignorePattern(rule.pattern, scopedPath) {
+			continue
+		}
+
+		for k, v := range rule.attributes {
+			attributes[k] = v
+		}
+	}
+
+	return attributes
+}
+
+// matchGitignorePattern reports whether a gitignore-style pattern matches
+// relPath. "**" matches any number of path segments; a pattern without a
+// "/" is matched against just the basename, mirroring gitignore semantics.
+func matchGitignorePattern(pattern, relPath string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		if matchSegments(pattern[1:], segments) {
+			return true
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		return matchSegments(pattern, segments[1:])
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// Skip reports whether relPath should be excluded from TODO scanning
+// because it is marked binary, generated, vendored, LFS-tracked, or
+// explicitly snitch-ignore'd.
+func (f *PathFilter) Skip(relPath string) bool {
+	attributes := f.attributesFor(relPath)
+
+	return attributes["snitch-ignore"] == "true" ||
+		attributes["binary"] == "true" ||
+		attributes["linguist-generated"] == "true" ||
+		attributes["linguist-vendored"] == "true" ||
+		strings.HasPrefix(attributes["filter"], "lfs")
+}
+
+// PrefixOverride returns the snitch-prefix=<PREFIX> attribute for relPath,
+// if one applies, letting a path locally override the project's
+// configured keyword(s) with a single custom one.
+func (f *PathFilter) PrefixOverride(relPath string) (prefix string, ok bool) {
+	prefix, ok = f.attributesFor(relPath)["snitch-prefix"]
+	return prefix, ok
+}
+
+// LooksBinary reports whether the first 8KB of filename contain a NUL
+// byte, the same heuristic git itself uses to guess whether a file is text.
+func LooksBinary(filename string) (bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 8192)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return bytes.IndexByte(buf[:n], 0) != -1, nil
+}
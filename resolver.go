@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// RemoteInfo is what RepoResolver extracted from a git remote URL: which
+// tracker provider to use, and the repo slug to pass to ResolveTracker
+// (host/owner/repo for self-hosted providers like Gitea, owner/repo
+// otherwise).
+type RemoteInfo struct {
+	Provider string
+	Repo     string
+}
+
+// RepoArg formats info the way ResolveTracker expects, e.g. "github:owner/repo".
+func (info RemoteInfo) RepoArg() string {
+	return info.Provider + ":" + info.Repo
+}
+
+// sshRemotePattern matches the scp-like syntax git uses for SSH remotes,
+// e.g. "git@github.com:owner/repo.git".
+var sshRemotePattern = regexp.MustCompile(`^(?:[^@]+@)?([^:/]+):(.+)$`)
+
+// RepoResolver auto-derives the issue tracker and repo slug to report to
+// from a git remote, so `snitch report`/`snitch watch` do not need an
+// explicit <owner/repo> argument in the common case.
+type RepoResolver struct {
+	RemoteName string
+}
+
+// DefaultRepoResolver resolves against the "origin" remote.
+func DefaultRepoResolver() RepoResolver {
+	return RepoResolver{RemoteName: "origin"}
+}
+
+// Resolve runs `git remote get-url <name>` and parses the result.
+func (r RepoResolver) Resolve() (RemoteInfo, error) {
+	out, err := LogCommand(exec.Command("git", "remote", "get-url", r.RemoteName)).Output()
+	if err != nil {
+		return RemoteInfo{}, err
+	}
+
+	return parseRemoteURL(strings.TrimSpace(string(out)))
+}
+
+// parseRemoteURL accepts both HTTP(S)/ssh:// remote URLs
+// ("https://gitea.example.com/owner/repo.git") and the scp-like SSH form
+// ("git@github.com:owner/repo.git"), and infers the provider from the
+// host: github.com and gitlab.com are recognized by name, anything else
+// is assumed to be a self-hosted Gitea/Gogs instance.
+func parseRemoteURL(remoteURL string) (RemoteInfo, error) {
+	host, path, err := splitRemoteURL(remoteURL)
+	if err != nil {
+		return RemoteInfo{}, err
+	}
+
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+
+	switch host {
+	case "github.com":
+		return RemoteInfo{Provider: "github", Repo: path}, nil
+	case "gitlab.com":
+		return RemoteInfo{Provider: "gitlab", Repo: path}, nil
+	default:
+		return RemoteInfo{Provider: "gitea", Repo: host + "/" + path}, nil
+	}
+}
+
+func splitRemoteURL(remoteURL string) (host, path string, err error) {
+	switch {
+	case strings.Contains(remoteURL, "://"):
+		rest := strings.SplitN(remoteURL, "://", 2)[1]
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("cannot parse remote URL %q", remoteURL)
+		}
+		return stripUserinfo(parts[0]), parts[1], nil
+	default:
+		groups := sshRemotePattern.FindStringSubmatch(remoteURL)
+		if groups == nil {
+			return "", "", fmt.Errorf("cannot parse remote URL %q", remoteURL)
+		}
+		return groups[1], groups[2], nil
+	}
+}
+
+func stripUserinfo(host string) string {
+	if at := strings.Index(host, "@"); at != -1 {
+		return host[at+1:]
+	}
+
+	return host
+}
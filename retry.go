@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// withRetry calls fn up to attempts times, backing off exponentially
+// between failures. It exists because issue tracker APIs occasionally
+// hiccup (rate limits, momentary 5xxs) and a single watch tick should not
+// abort over that.
+func withRetry(attempts int, backoff time.Duration, fn func() error) error {
+	var err error
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if attempt < attempts-1 {
+			fmt.Printf("[RETRY] %v (attempt %d/%d)\n", err, attempt+1, attempts)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return err
+}
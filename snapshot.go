@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SnapshotEntry tracks what `snitch watch` last knew about a reported TODO.
+type SnapshotEntry struct {
+	ID       string `json:"id"`
+	State    string `json:"state"`
+	Filename string `json:"filename"`
+
+	// TicksSinceCheck counts ticks since the tracker was last asked for
+	// this entry's Status while it was "open", so watchTick can throttle
+	// how often it re-queries a TODO it already knows is open.
+	TicksSinceCheck int `json:"ticksSinceCheck"`
+}
+
+// Snapshot maps a TODO's location+content key to the last known state of its
+// reported issue, so `snitch watch` does not have to re-query the tracker
+// for every TODO on every tick.
+type Snapshot map[string]SnapshotEntry
+
+// snapshotKey identifies a TODO by where it is and what it says. It
+// includes the line number, so an edit that merely shifts a TODO up or
+// down the file (e.g. because an earlier TODO in the same file was closed
+// and removed) changes the key -- watchTick guards against treating that
+// shift as a deletion by also checking the TODO's issue ID, which does not
+// change, before closing anything upstream.
+func snapshotKey(todo Todo) string {
+	sum := sha256.Sum256([]byte(todo.Prefix + todo.Suffix))
+	return fmt.Sprintf("%s:%d:%s", todo.Filename, todo.Line, hex.EncodeToString(sum[:])[:12])
+}
+
+// LoadSnapshot reads a snapshot file written by a previous `snitch watch`
+// run. A missing file yields an empty Snapshot.
+func LoadSnapshot(path string) (Snapshot, error) {
+	snapshot := Snapshot{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Save writes the snapshot to path, creating its parent directory if
+// necessary.
+func (snapshot Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
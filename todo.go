@@ -3,24 +3,32 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
-	"strconv"
+	"time"
 )
 
 // Todo contains information about a TODO in the repo
 type Todo struct {
+	Keyword  string
 	Prefix   string
 	Suffix   string
 	ID       *string
 	Filename string
 	Line     int
 	Title    string
+	Labels   []string
+
+	// Author, AuthorEmail, Commit and AuthoredAt describe the git blame of
+	// the line the TODO was introduced on.
+	Author      string
+	AuthorEmail string
+	Commit      string
+	AuthoredAt  time.Time
 }
 
 // LogString formats TODO for compilation logging. Format is
@@ -28,24 +36,24 @@ type Todo struct {
 // between the todos.
 func (todo Todo) LogString() string {
 	if todo.ID == nil {
-		return fmt.Sprintf("%s:%d: %sTODO: %s",
+		return fmt.Sprintf("%s:%d: %s%s: %s",
 			todo.Filename, todo.Line,
-			todo.Prefix, todo.Suffix)
+			todo.Prefix, todo.Keyword, todo.Suffix)
 	}
 
-	return fmt.Sprintf("%s:%d: %sTODO(%s): %s",
+	return fmt.Sprintf("%s:%d: %s%s(%s): %s",
 		todo.Filename, todo.Line,
-		todo.Prefix, *todo.ID, todo.Suffix)
+		todo.Prefix, todo.Keyword, *todo.ID, todo.Suffix)
 }
 
 func (todo Todo) String() string {
 	if todo.ID == nil {
-		return fmt.Sprintf("%sTODO: %s",
-			todo.Prefix, todo.Suffix)
+		return fmt.Sprintf("%s%s: %s",
+			todo.Prefix, todo.Keyword, todo.Suffix)
 	}
 
-	return fmt.Sprintf("%sTODO(%s): %s",
-		todo.Prefix, *todo.ID, todo.Suffix)
+	return fmt.Sprintf("%s%s(%s): %s",
+		todo.Prefix, todo.Keyword, *todo.ID, todo.Suffix)
 }
 
 func (todo Todo) updateToFile(outputFilename string, lineCallback func(int, string) (string, bool)) error {
@@ -132,73 +140,94 @@ func (todo Todo) GitCommit(prefix string) error {
 		return err
 	}
 
-	if err := LogCommand(exec.Command("git", "commit", "-m", fmt.Sprintf("%s TODO(%s)", prefix, *todo.ID))).Run(); err != nil {
+	if err := LogCommand(exec.Command("git", "commit", "-m", fmt.Sprintf("%s %s(%s)", prefix, todo.Keyword, *todo.ID))).Run(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func lineAsUnreportedTodo(projectConfig ProjectConfig, line string) *Todo {
-	unreportedTodo := regexp.MustCompile("^(.*)TODO: (.*)$")
-	groups := unreportedTodo.FindStringSubmatch(line)
+// lineAsReportedTodo matches "KEYWORD(#123): suffix". The id must look
+// like "#<digits>" -- anything else inside the parens is a severity
+// annotation handled by lineAsUnreportedTodo instead.
+func lineAsReportedTodo(projectConfig ProjectConfig, keyword, line string) *Todo {
+	reportedTodo := regexp.MustCompile("^(.*)" + regexp.QuoteMeta(keyword) + `\((#\d+)\): (.*)$`)
+	groups := reportedTodo.FindStringSubmatch(line)
 
-	if groups != nil {
-		prefix := groups[1]
-		suffix := groups[2]
-		title := projectConfig.Title.Transform(suffix)
-
-		return &Todo{
-			Prefix:   prefix,
-			Suffix:   suffix,
-			ID:       nil,
-			Filename: "",
-			Line:     0,
-			Title:    title,
-		}
+	if groups == nil {
+		return nil
 	}
 
-	return nil
+	prefix := groups[1]
+	id := groups[2]
+	suffix := groups[3]
+	kw := projectConfig.Keyword(keyword)
+
+	todo := &Todo{
+		Keyword: keyword,
+		Prefix:  prefix,
+		Suffix:  suffix,
+		ID:      &id,
+		Labels:  append([]string(nil), kw.Labels...),
+	}
+	todo.Title = kw.RenderTitle(*todo)
+
+	return todo
 }
 
-func lineAsReportedTodo(projectConfig ProjectConfig, line string) *Todo {
-	unreportedTodo := regexp.MustCompile("^(.*)TODO\\((.*)\\): (.*)$")
+// lineAsUnreportedTodo matches "KEYWORD: suffix" and the annotated form
+// "KEYWORD(annotation): suffix", where annotation (e.g. "!!!") is fed to
+// the project's severity rules rather than treated as an issue id.
+func lineAsUnreportedTodo(projectConfig ProjectConfig, keyword, line string) *Todo {
+	unreportedTodo := regexp.MustCompile("^(.*)" + regexp.QuoteMeta(keyword) + `(?:\(([^)]*)\))?: (.*)$`)
 	groups := unreportedTodo.FindStringSubmatch(line)
 
-	if groups != nil {
-		prefix := groups[1]
-		suffix := groups[3]
-		id := groups[2]
-		title := projectConfig.Title.Transform(suffix)
-
-		return &Todo{
-			Prefix:   prefix,
-			Suffix:   suffix,
-			ID:       &id,
-			Filename: "",
-			Line:     0,
-			Title:    title,
-		}
+	if groups == nil {
+		return nil
 	}
 
-	return nil
+	prefix := groups[1]
+	annotation := groups[2]
+	suffix := groups[3]
+	kw := projectConfig.Keyword(keyword)
+
+	labels := append([]string(nil), kw.Labels...)
+	labels = append(labels, projectConfig.SeverityLabels(annotation, suffix)...)
+
+	todo := &Todo{
+		Keyword: keyword,
+		Prefix:  prefix,
+		Suffix:  suffix,
+		Labels:  labels,
+	}
+	todo.Title = kw.RenderTitle(*todo)
+
+	return todo
 }
 
-// LineAsTodo constructs a Todo from a string
-func LineAsTodo(projectConfig ProjectConfig, line string) *Todo {
-	if todo := lineAsUnreportedTodo(projectConfig, line); todo != nil {
+// LineAsTodo constructs a Todo from a string, recognizing keyword (e.g.
+// "TODO" or "FIXME") as the marker. The reported form is tried first
+// since it is the more specific of the two.
+func LineAsTodo(projectConfig ProjectConfig, keyword, line string) *Todo {
+	if todo := lineAsReportedTodo(projectConfig, keyword, line); todo != nil {
 		return todo
 	}
 
-	if todo := lineAsReportedTodo(projectConfig, line); todo != nil {
+	if todo := lineAsUnreportedTodo(projectConfig, keyword, line); todo != nil {
 		return todo
 	}
 
 	return nil
 }
 
-// WalkTodosOfFile visits all of the TODOs in a particular file
-func WalkTodosOfFile(projectConfig ProjectConfig, path string, visit func(Todo) error) error {
+// WalkTodosOfFile visits all of the TODOs in a particular file. keywords
+// are the markers to look for, tried in order on every line (usually just
+// "TODO", but a project can recognize several, and a path can override
+// them to a single one via the snitch-prefix gitattribute). withBlame runs
+// `git blame` on every matched line to fill in the Todo's Author/Commit
+// fields; pass false for read-only listing, where that cost (a subprocess
+// per TODO) buys nothing.
+func WalkTodosOfFile(projectConfig ProjectConfig, keywords []string, path string, withBlame bool, visit func(Todo) error) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -209,12 +238,28 @@ func WalkTodosOfFile(projectConfig ProjectConfig, path string, visit func(Todo)
 
 	text, _, err := reader.ReadLine()
 	for line := 1; err == nil; line = line + 1 {
-		todo := LineAsTodo(projectConfig, string(text))
+		var todo *Todo
+		for _, keyword := range keywords {
+			if todo = LineAsTodo(projectConfig, keyword, string(text)); todo != nil {
+				break
+			}
+		}
 
 		if todo != nil {
 			todo.Filename = path
 			todo.Line = line
 
+			if withBlame {
+				blame, err := BlameLine(path, line)
+				if err != nil {
+					return err
+				}
+				todo.Author = blame.Author
+				todo.AuthorEmail = blame.AuthorEmail
+				todo.Commit = blame.Commit
+				todo.AuthoredAt = blame.AuthoredAt
+			}
+
 			if err := visit(*todo); err != nil {
 				return err
 			}
@@ -230,8 +275,17 @@ func WalkTodosOfFile(projectConfig ProjectConfig, path string, visit func(Todo)
 	return nil
 }
 
-// WalkTodosOfDir visits all of the TODOs in a particular directory
-func WalkTodosOfDir(projectConfig ProjectConfig, dirpath string, visit func(todo Todo) error) error {
+// WalkTodosOfDir visits all of the TODOs in a particular directory.
+// Besides the files git itself ignores, it also skips paths excluded by
+// .gitattributes (binary, generated, vendored, LFS-tracked or
+// snitch-ignore'd) and anything that looks like a binary blob.
+//
+// Each file's project config is resolved from root down to the file's own
+// directory (see LoadProjectConfig), so a nested .snitch.yaml can add
+// keywords or override templates for its own subtree; visit is handed the
+// config that applies to the TODO it was called with. withBlame is passed
+// through to WalkTodosOfFile.
+func WalkTodosOfDir(root, dirpath string, withBlame bool, visit func(config ProjectConfig, todo Todo) error) error {
 	cmd := exec.Command("git", "ls-files", dirpath)
 	var outb bytes.Buffer
 	cmd.Stdout = &outb
@@ -241,80 +295,88 @@ func WalkTodosOfDir(projectConfig ProjectConfig, dirpath string, visit func(todo
 		return err
 	}
 
+	filter, err := NewPathFilter(".")
+	if err != nil {
+		return err
+	}
+
+	configCache := map[string]ProjectConfig{}
+
 	scanner := bufio.NewScanner(&outb)
 
 	for scanner.Scan() {
 		filepath := scanner.Text()
-		err = WalkTodosOfFile(projectConfig, filepath, visit)
-		if err != nil {
+
+		if filter.Skip(filepath) {
+			continue
+		}
+
+		if binary, err := LooksBinary(filepath); err != nil {
 			return err
+		} else if binary {
+			continue
 		}
-	}
 
-	return err
-}
+		projectConfig, err := configForDir(configCache, root, path.Dir(filepath))
+		if err != nil {
+			return err
+		}
 
-func queryGithubAPI(creds GithubCredentials, method, url string, jsonBody map[string]interface{}) (map[string]interface{}, error) {
-	client := &http.Client{}
+		keywords := projectConfig.KeywordNames()
+		if len(keywords) == 0 {
+			keywords = []string{"TODO"}
+		}
 
-	bodyBuffer := new(bytes.Buffer)
-	err := json.NewEncoder(bodyBuffer).Encode(jsonBody)
+		fileKeywords := keywords
+		if prefix, ok := filter.PrefixOverride(filepath); ok {
+			fileKeywords = []string{prefix}
+		}
 
-	req, err := http.NewRequest(
-		method, url, bodyBuffer)
-	if err != nil {
-		return nil, err
+		err = WalkTodosOfFile(projectConfig, fileKeywords, filepath, withBlame, func(todo Todo) error {
+			return visit(projectConfig, todo)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	req.Header.Add("Authorization", "token "+creds.PersonalToken)
-	req.Header.Add("Content-Type", "application/json")
+	return err
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// configForDir resolves and caches the ProjectConfig for dir (relative to
+// root), so directories visited more than once don't re-read and re-merge
+// the same .snitch.yaml files.
+func configForDir(cache map[string]ProjectConfig, root, dir string) (ProjectConfig, error) {
+	if config, ok := cache[dir]; ok {
+		return config, nil
 	}
-	defer resp.Body.Close()
 
-	var v map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
-		return nil, err
+	config, err := LoadProjectConfig(root, dir)
+	if err != nil {
+		return ProjectConfig{}, err
 	}
 
-	return v, err
+	cache[dir] = config
+	return config, nil
 }
 
-// RetrieveGithubStatus retrieves the current status of TODOs issue
-// from GitHub
-func (todo Todo) RetrieveGithubStatus(creds GithubCredentials, repo string) (string, error) {
-	json, err := queryGithubAPI(
-		creds,
-		"GET",
-		// TODO(#59): possible GitHub API injection attack
-		"https://api.github.com/repos/"+repo+"/issues/"+(*todo.ID)[1:],
-		nil)
-
+// Report files the todo as an issue through tracker and returns a copy of
+// the todo carrying the new issue ID. It neither updates the file where the
+// todo is located nor commits anything; callers do that with Update and
+// GitCommit.
+func (todo Todo) Report(tracker IssueTracker, body string) (Todo, error) {
+	id, err := tracker.Report(todo, body)
 	if err != nil {
-		return "", err
+		return todo, err
 	}
 
-	return json["state"].(string), nil
-}
-
-// ReportTodo reports the todo as a Github Issue, updates the file
-// where the todo is located and commits the changes to the git repo.
-func (todo Todo) ReportTodo(creds GithubCredentials, repo string, body string) (Todo, error) {
-	// TODO(#60): ReportTodo is not a Todo method
-	json, err := queryGithubAPI(
-		creds,
-		"POST",
-		"https://api.github.com/repos/"+repo+"/issues",
-		map[string]interface{}{
-			"title": todo.Title,
-			"body":  body,
-		})
-
-	id := "#" + strconv.Itoa(int(json["number"].(float64)))
 	todo.ID = &id
 
-	return todo, err
+	return todo, nil
+}
+
+// RetrieveStatus retrieves the current status of the todo's issue from
+// tracker.
+func (todo Todo) RetrieveStatus(tracker IssueTracker) (string, error) {
+	return tracker.Status(todo)
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueTracker is implemented by every issue tracker backend snitch can
+// report TODOs to.
+type IssueTracker interface {
+	// Report files todo as a new issue with the given body and returns the
+	// tracker-assigned issue id (e.g. "#42").
+	Report(todo Todo, body string) (id string, err error)
+	// Status returns the current state (e.g. "open", "closed") of the issue
+	// todo was reported to.
+	Status(todo Todo) (state string, err error)
+	// Close closes the issue with the given tracker-assigned id.
+	Close(id string) error
+	// IssueURL returns the URL a human can open to view the issue todo was
+	// reported to.
+	IssueURL(todo Todo) string
+}
+
+// ResolveTracker picks the IssueTracker backend for repoArg. repoArg is
+// either a bare "owner/repo" (GitHub, for backwards compatibility) or
+// prefixed with the provider name, e.g. "gitlab:owner/repo" or
+// "gitea:host/owner/repo".
+func ResolveTracker(creds Credentials, repoArg string) (IssueTracker, error) {
+	provider, rest := splitProvider(repoArg)
+
+	switch provider {
+	case "github":
+		return GithubTracker{Creds: creds.Github, Repo: rest}, nil
+	case "gitlab":
+		return GitlabTracker{Creds: creds.Gitlab, Repo: rest}, nil
+	case "gitea":
+		host, repo, err := splitHostRepo(rest)
+		if err != nil {
+			return nil, err
+		}
+		if host == "" {
+			host = creds.Gitea.Host
+		}
+		return GiteaTracker{Creds: creds.Gitea, Host: host, Repo: repo}, nil
+	default:
+		return nil, fmt.Errorf("unknown issue tracker provider %q", provider)
+	}
+}
+
+func splitProvider(repoArg string) (provider, rest string) {
+	if idx := strings.Index(repoArg, ":"); idx != -1 {
+		return repoArg[:idx], repoArg[idx+1:]
+	}
+
+	return "github", repoArg
+}
+
+// splitHostRepo splits "host/owner/repo" into "host" and "owner/repo".
+func splitHostRepo(rest string) (host, repo string, err error) {
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected host/owner/repo, got %q", rest)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	watchSnapshotPath  = ".snitch/snapshot.json"
+	watchInterval      = 30 * time.Second
+	watchRetryAttempts = 3
+	watchRetryBackoff  = 2 * time.Second
+
+	// watchStatusCheckEvery caps how often a TODO we already know is open
+	// gets re-queried against the tracker: every tick would mean N API
+	// calls per tick for N reported TODOs, forever, which is exactly what
+	// the snapshot is meant to avoid.
+	watchStatusCheckEvery = 10
+)
+
+// watchSubcommand keeps scanning the working tree and reports/closes TODOs
+// incrementally: on startup it loads (or creates) a snapshot of what it
+// already knows, then on every tick it diffs the tree against that
+// snapshot: it files new TODOs, closes the issues of vanished ones, and
+// re-queries the status of a TODO already known to be open only once every
+// watchStatusCheckEvery ticks.
+func watchSubcommand(creds Credentials, repoArg string) error {
+	snapshot, err := LoadSnapshot(watchSnapshotPath)
+	if err != nil {
+		return err
+	}
+
+	for {
+		snapshot, err = watchTick(creds, repoArg, snapshot)
+		if err != nil {
+			return err
+		}
+
+		if err := snapshot.Save(watchSnapshotPath); err != nil {
+			return err
+		}
+
+		time.Sleep(watchInterval)
+	}
+}
+
+// watchTick walks the tree once and reconciles it against snapshot:
+//   - newly appeared unreported TODOs are filed as issues
+//   - reported TODOs whose source line disappeared have their issue closed
+//   - reported TODOs whose issue was closed upstream are removed locally
+//
+// It returns the snapshot to persist for the next tick.
+func watchTick(creds Credentials, repoArg string, snapshot Snapshot) (Snapshot, error) {
+	seen := Snapshot{}
+
+	err := WalkTodosOfDir(".", ".", true, func(projectConfig ProjectConfig, todo Todo) error {
+		tracker, err := trackerFor(creds, projectConfig, repoArg, todo)
+		if err != nil {
+			return err
+		}
+
+		if todo.ID == nil {
+			body, err := projectConfig.Keyword(todo.Keyword).RenderBody(todo)
+			if err != nil {
+				return err
+			}
+
+			if mentions := projectConfig.Mentions(todo); len(mentions) > 0 {
+				body = "@" + strings.Join(mentions, " @") + " " + body
+			}
+
+			var id string
+			if err := withRetry(watchRetryAttempts, watchRetryBackoff, func() error {
+				var reportErr error
+				id, reportErr = tracker.Report(todo, body)
+				return reportErr
+			}); err != nil {
+				return err
+			}
+
+			todo.ID = &id
+			if err := todo.Update(); err != nil {
+				return err
+			}
+			if err := todo.GitCommit("Report"); err != nil {
+				return err
+			}
+
+			fmt.Printf("[REPORTED] %v\n", todo.LogString())
+			seen[snapshotKey(todo)] = SnapshotEntry{ID: id, State: "open", Filename: todo.Filename}
+			return nil
+		}
+
+		key := snapshotKey(todo)
+		prev, known := snapshot[key]
+
+		state := "open"
+		ticksSinceCheck := 0
+		if known && prev.State == "open" && prev.TicksSinceCheck+1 < watchStatusCheckEvery {
+			// We already know this TODO is open and checked recently;
+			// skip hitting the tracker again this tick.
+			state = prev.State
+			ticksSinceCheck = prev.TicksSinceCheck + 1
+		} else if err := withRetry(watchRetryAttempts, watchRetryBackoff, func() error {
+			var statusErr error
+			state, statusErr = tracker.Status(todo)
+			return statusErr
+		}); err != nil {
+			return err
+		}
+
+		if state == "closed" {
+			if err := todo.Remove(); err != nil {
+				return err
+			}
+			if err := todo.GitCommit("Close"); err != nil {
+				return err
+			}
+
+			fmt.Printf("[CLOSED] %v\n", todo.LogString())
+			// Mark handled so the vanished-line loop below does not see a
+			// stale "open" entry for this key and re-close it upstream.
+			seen[key] = SnapshotEntry{ID: *todo.ID, State: "closed", Filename: todo.Filename}
+			return nil
+		}
+
+		seen[key] = SnapshotEntry{ID: *todo.ID, State: state, Filename: todo.Filename, TicksSinceCheck: ticksSinceCheck}
+		return nil
+	})
+	if err != nil {
+		return snapshot, err
+	}
+
+	seenIDs := map[string]bool{}
+	for _, entry := range seen {
+		seenIDs[entry.ID] = true
+	}
+
+	for key, entry := range snapshot {
+		if _, stillThere := seen[key]; stillThere || entry.State == "closed" {
+			continue
+		}
+
+		if seenIDs[entry.ID] {
+			// snapshotKey bakes in the TODO's line number, so a TODO that
+			// merely shifted up or down the file (e.g. because an earlier
+			// TODO in the same file was closed and removed) looks like it
+			// vanished under its old key. It's still there under a new
+			// one, which seen already carries the authoritative state
+			// for, so don't close its issue out from under it.
+			continue
+		}
+
+		// The TODO's source line vanished between ticks without going
+		// through Remove (e.g. the whole file was deleted), so close its
+		// issue upstream to keep the tracker honest.
+		entryConfig, err := LoadProjectConfig(".", filepath.Dir(entry.Filename))
+		if err != nil {
+			return snapshot, err
+		}
+
+		tracker, err := trackerFor(creds, entryConfig, repoArg, Todo{Filename: entry.Filename})
+		if err != nil {
+			return snapshot, err
+		}
+
+		if err := withRetry(watchRetryAttempts, watchRetryBackoff, func() error {
+			return tracker.Close(entry.ID)
+		}); err != nil {
+			return snapshot, err
+		}
+
+		fmt.Printf("[CLOSED UPSTREAM] %s\n", entry.ID)
+	}
+
+	return seen, nil
+}
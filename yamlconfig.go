@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const projectConfigFilename = ".snitch.yaml"
+
+// rawProjectConfig is the YAML shape of a .snitch.yaml file.
+type rawProjectConfig struct {
+	Keywords   map[string]KeywordConfig `yaml:"keywords"`
+	Severities []SeverityRule           `yaml:"severities"`
+	Routes     []Route                  `yaml:"routes"`
+	Assignees  map[string]string        `yaml:"assignees"`
+}
+
+// LoadProjectConfig builds a ProjectConfig for dir by reading every
+// .snitch.yaml between the repo root and dir and merging them, root
+// first, so a deeper directory's rules override (for keywords/assignees)
+// or add to (for severities/routes) the ones above it. A tree with no
+// .snitch.yaml at all falls back to DefaultProjectConfig.
+func LoadProjectConfig(root, dir string) (ProjectConfig, error) {
+	relDir, err := filepath.Rel(root, dir)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+
+	dirs := []string{root}
+	if relDir != "." {
+		walked := root
+		for _, segment := range splitPath(relDir) {
+			walked = filepath.Join(walked, segment)
+			dirs = append(dirs, walked)
+		}
+	}
+
+	config := ProjectConfig{Keywords: map[string]KeywordConfig{}}
+	found := false
+
+	for _, d := range dirs {
+		raw, ok, err := readRawProjectConfig(filepath.Join(d, projectConfigFilename))
+		if err != nil {
+			return ProjectConfig{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		found = true
+
+		for name, kw := range raw.Keywords {
+			config.Keywords[name] = kw
+		}
+
+		config.Severities = append(config.Severities, raw.Severities...)
+		config.Routes = append(config.Routes, raw.Routes...)
+
+		if config.Assignees == nil {
+			config.Assignees = map[string]string{}
+		}
+		for email, handle := range raw.Assignees {
+			config.Assignees[email] = handle
+		}
+	}
+
+	if !found {
+		return DefaultProjectConfig(), nil
+	}
+
+	if len(config.Keywords) == 0 {
+		config.Keywords["TODO"] = KeywordConfig{}
+	}
+
+	if err := compileProjectConfig(&config); err != nil {
+		return ProjectConfig{}, err
+	}
+
+	return config, nil
+}
+
+// splitPath breaks a relative directory path into its individual
+// components, e.g. "services/api" -> ["services", "api"].
+func splitPath(relDir string) []string {
+	var parts []string
+
+	relDir = filepath.Clean(relDir)
+	for relDir != "." && relDir != string(filepath.Separator) && relDir != "" {
+		dir, file := filepath.Split(relDir)
+		parts = append([]string{file}, parts...)
+		relDir = filepath.Clean(dir)
+	}
+
+	return parts
+}
+
+func readRawProjectConfig(path string) (rawProjectConfig, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rawProjectConfig{}, false, nil
+		}
+		return rawProjectConfig{}, false, err
+	}
+
+	var raw rawProjectConfig
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return rawProjectConfig{}, false, err
+	}
+
+	return raw, true, nil
+}
+
+// compileProjectConfig compiles every keyword's title/body templates and
+// every severity rule's regexp, so matching and rendering at scan time is
+// just a lookup.
+func compileProjectConfig(config *ProjectConfig) error {
+	for name, kw := range config.Keywords {
+		if kw.Title != "" {
+			tmpl, err := template.New(name + "-title").Parse(kw.Title)
+			if err != nil {
+				return err
+			}
+			kw.titleTemplate = tmpl
+		}
+
+		if kw.Body != "" {
+			tmpl, err := template.New(name + "-body").Parse(kw.Body)
+			if err != nil {
+				return err
+			}
+			kw.bodyTemplate = tmpl
+		}
+
+		config.Keywords[name] = kw
+	}
+
+	for i, rule := range config.Severities {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return err
+		}
+		config.Severities[i].regexp = re
+	}
+
+	return nil
+}